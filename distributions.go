@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Distribution samples a duration (in whole minutes) for an
+// inter-arrival or service time.
+type Distribution interface {
+	Sample() int
+}
+
+type Exponential struct {
+	rng    *rand.Rand
+	lambda float64
+}
+
+func (e *Exponential) Sample() int {
+	return int(-math.Log(1.0-e.rng.Float64()) / e.lambda)
+}
+
+type Deterministic struct {
+	value float64
+}
+
+func (d *Deterministic) Sample() int {
+	return int(d.value)
+}
+
+// ErlangK sums k independent exponential phases, each with rate k/mean.
+type ErlangK struct {
+	rng  *rand.Rand
+	k    int
+	rate float64
+}
+
+func NewErlangK(rng *rand.Rand, k int, mean float64) *ErlangK {
+	return &ErlangK{rng: rng, k: k, rate: float64(k) / mean}
+}
+
+func (e *ErlangK) Sample() int {
+	sum := 0.0
+	for i := 0; i < e.k; i++ {
+		sum += -math.Log(1.0-e.rng.Float64()) / e.rate
+	}
+	return int(sum)
+}
+
+// Lognormal draws a standard normal via Box-Muller, scales it by
+// (mu, sigma), and exponentiates it.
+type Lognormal struct {
+	rng   *rand.Rand
+	mu    float64
+	sigma float64
+}
+
+func (l *Lognormal) Sample() int {
+	u1, u2 := l.rng.Float64(), l.rng.Float64()
+	z := math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+	return int(math.Exp(l.mu + l.sigma*z))
+}
+
+// Hyperexponential mixes two exponentials, picking the first with
+// probability p.
+type Hyperexponential struct {
+	rng   *rand.Rand
+	p     float64
+	rate1 float64
+	rate2 float64
+}
+
+func (h *Hyperexponential) Sample() int {
+	rate := h.rate2
+	if h.rng.Float64() < h.p {
+		rate = h.rate1
+	}
+	return int(-math.Log(1.0-h.rng.Float64()) / rate)
+}
+
+// DistSpec is a parsed "name:param,param,..." specifier, e.g. "exp:1.5"
+// or "lognormal:5,1.2".
+type DistSpec struct {
+	Name   string
+	Params []float64
+}
+
+func parseDistSpec(s string) (DistSpec, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return DistSpec{}, fmt.Errorf("expected name:params, got %q", s)
+	}
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	paramStrs := strings.Split(parts[1], ",")
+	params := make([]float64, len(paramStrs))
+	for i, ps := range paramStrs {
+		v, err := strconv.ParseFloat(strings.TrimSpace(ps), 64)
+		if err != nil {
+			return DistSpec{}, fmt.Errorf("param %q: %w", ps, err)
+		}
+		params[i] = v
+	}
+	return DistSpec{Name: name, Params: params}, nil
+}
+
+// parseDistSpecList parses a ";"-separated list of DistSpecs; "," can't
+// be the list separator since it's already used within a spec's params.
+func parseDistSpecList(s string) ([]DistSpec, error) {
+	var specs []DistSpec
+	for _, part := range strings.Split(s, ";") {
+		spec, err := parseDistSpec(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func newDistribution(spec DistSpec, rng *rand.Rand) (Distribution, error) {
+	switch spec.Name {
+	case "exp", "exponential":
+		if len(spec.Params) != 1 {
+			return nil, fmt.Errorf("exponential requires 1 param (lambda), got %d", len(spec.Params))
+		}
+		return &Exponential{rng: rng, lambda: spec.Params[0]}, nil
+	case "det", "deterministic":
+		if len(spec.Params) != 1 {
+			return nil, fmt.Errorf("deterministic requires 1 param (value), got %d", len(spec.Params))
+		}
+		if spec.Params[0] < 1 {
+			return nil, fmt.Errorf("deterministic value must be >= 1 (truncates to whole minutes), got %v", spec.Params[0])
+		}
+		return &Deterministic{value: spec.Params[0]}, nil
+	case "erlang":
+		if len(spec.Params) != 2 {
+			return nil, fmt.Errorf("erlang requires 2 params (k, mean), got %d", len(spec.Params))
+		}
+		if k := int(spec.Params[0]); k < 1 {
+			return nil, fmt.Errorf("erlang k must be >= 1, got %d", k)
+		}
+		return NewErlangK(rng, int(spec.Params[0]), spec.Params[1]), nil
+	case "lognormal":
+		if len(spec.Params) != 2 {
+			return nil, fmt.Errorf("lognormal requires 2 params (mu, sigma), got %d", len(spec.Params))
+		}
+		return &Lognormal{rng: rng, mu: spec.Params[0], sigma: spec.Params[1]}, nil
+	case "hyperexp", "hyperexponential":
+		if len(spec.Params) != 3 {
+			return nil, fmt.Errorf("hyperexponential requires 3 params (p, rate1, rate2), got %d", len(spec.Params))
+		}
+		return &Hyperexponential{rng: rng, p: spec.Params[0], rate1: spec.Params[1], rate2: spec.Params[2]}, nil
+	default:
+		return nil, fmt.Errorf("unknown distribution %q", spec.Name)
+	}
+}
+
+// distCV returns the theoretical coefficient of variation (stddev/mean)
+// for a distribution spec.
+func distCV(spec DistSpec) float64 {
+	switch spec.Name {
+	case "exp", "exponential":
+		return 1.0
+	case "det", "deterministic":
+		return 0.0
+	case "erlang":
+		return 1.0 / math.Sqrt(spec.Params[0])
+	case "lognormal":
+		sigma := spec.Params[1]
+		return math.Sqrt(math.Exp(sigma*sigma) - 1)
+	case "hyperexp", "hyperexponential":
+		p, rate1, rate2 := spec.Params[0], spec.Params[1], spec.Params[2]
+		mean := p/rate1 + (1-p)/rate2
+		secondMoment := 2*p/(rate1*rate1) + 2*(1-p)/(rate2*rate2)
+		variance := secondMoment - mean*mean
+		return math.Sqrt(variance) / mean
+	default:
+		return 0
+	}
+}
@@ -2,14 +2,14 @@ package main
 
 import (
 	"container/heap"
+	"context"
 	"encoding/csv"
 	"fmt"
 	"log"
-	"math"
-	"math/rand"
 	"os"
-	"strconv"
-	"time"
+	"os/signal"
+	"runtime/pprof"
+	"syscall"
 )
 
 type Event struct {
@@ -46,14 +46,20 @@ type CallCenter struct {
 	callQueue       []*Event
 	logs            []string
 	maxWaitTime     int
+	warmupTime      int
 	totalCalls      int
 	abandonedCalls  int
 	busyTime        []int
-	lambda          float64
-	averageCallTime float64
+	arrivalDist     Distribution
+	serviceDist     Distribution
+
+	arrivalTimes       map[int]int
+	waitTimes          []int
+	sojournTimes       []int
+	abandonedWaitTimes []int
 }
 
-func NewCallCenter(numAgents int, maxWaitTime int, lambda, averageCallTime float64) *CallCenter {
+func NewCallCenter(numAgents int, maxWaitTime int, arrivalDist, serviceDist Distribution, warmupTime int) *CallCenter {
 	return &CallCenter{
 		numAgents:       numAgents,
 		availableAgents: make([]bool, numAgents),
@@ -63,9 +69,11 @@ func NewCallCenter(numAgents int, maxWaitTime int, lambda, averageCallTime float
 		callQueue:       []*Event{},
 		logs:            []string{},
 		maxWaitTime:     maxWaitTime,
+		warmupTime:      warmupTime,
 		busyTime:        make([]int, numAgents),
-		lambda:          lambda,
-		averageCallTime: averageCallTime,
+		arrivalDist:     arrivalDist,
+		serviceDist:     serviceDist,
+		arrivalTimes:    make(map[int]int),
 	}
 }
 
@@ -94,6 +102,7 @@ func (cc *CallCenter) ProcessNextEvent() {
 
 func (cc *CallCenter) handleCallArrival(event *Event) {
 	cc.totalCalls++
+	cc.arrivalTimes[event.callID] = cc.currentTime
 	if cc.assignAgentToCall(event) {
 		return
 	}
@@ -105,7 +114,8 @@ func (cc *CallCenter) assignAgentToCall(event *Event) bool {
 	for i := 0; i < cc.numAgents; i++ {
 		if !cc.availableAgents[i] {
 			cc.availableAgents[i] = true
-			callDuration := exponential(1.0 / cc.averageCallTime)
+			cc.waitTimes = append(cc.waitTimes, cc.currentTime-cc.arrivalTimes[event.callID])
+			callDuration := cc.serviceDist.Sample()
 			cc.ScheduleEvent(cc.currentTime+callDuration, "CallCompletion", event.callID, i)
 			cc.busyTime[i] += callDuration
 			return true
@@ -116,6 +126,8 @@ func (cc *CallCenter) assignAgentToCall(event *Event) bool {
 
 func (cc *CallCenter) handleCallCompletion(event *Event) {
 	cc.availableAgents[event.agentID] = false
+	cc.sojournTimes = append(cc.sojournTimes, cc.currentTime-cc.arrivalTimes[event.callID])
+	delete(cc.arrivalTimes, event.callID)
 	if len(cc.callQueue) > 0 {
 		nextCall := cc.callQueue[0]
 		cc.callQueue = cc.callQueue[1:]
@@ -128,76 +140,123 @@ func (cc *CallCenter) handleCallAbandonment(event *Event) {
 		if call.callID == event.callID {
 			cc.callQueue = append(cc.callQueue[:i], cc.callQueue[i+1:]...)
 			cc.abandonedCalls++
+			cc.abandonedWaitTimes = append(cc.abandonedWaitTimes, cc.currentTime-cc.arrivalTimes[event.callID])
+			delete(cc.arrivalTimes, event.callID)
 			break
 		}
 	}
 }
 
-func exponential(lambda float64) int {
-	return int(-math.Log(1.0-rand.Float64()) / lambda)
+// resetStatsAfterWarmup discards stats accumulated during the warmup window.
+func (cc *CallCenter) resetStatsAfterWarmup() {
+	cc.totalCalls = 0
+	cc.abandonedCalls = 0
+	cc.busyTime = make([]int, cc.numAgents)
+	cc.waitTimes = nil
+	cc.sojournTimes = nil
+	cc.abandonedWaitTimes = nil
 }
 
-func (cc *CallCenter) RunSimulation(simulationTime int) (int, int, float64) {
+func (cc *CallCenter) RunSimulation(ctx context.Context, simulationTime int) Stats {
 	heap.Init(&cc.eventQueue)
+	totalHorizon := cc.warmupTime + simulationTime
 	t := 0
-	for t < simulationTime {
-		interArrivalTime := exponential(cc.lambda)
+pregen:
+	for t < totalHorizon {
+		select {
+		case <-ctx.Done():
+			break pregen
+		default:
+		}
+		interArrivalTime := cc.arrivalDist.Sample()
+		if interArrivalTime < 1 {
+			// Guard against a misbehaving Distribution returning a
+			// non-positive sample, which would otherwise leave t stuck
+			// and grow the event heap without bound.
+			interArrivalTime = 1
+		}
 		t += interArrivalTime
 		cc.callCounter++
 		cc.ScheduleEvent(t, "CallArrival", cc.callCounter, -1)
 	}
-	for len(cc.eventQueue) > 0 && cc.currentTime < simulationTime {
+	warmedUp := cc.warmupTime <= 0
+	for len(cc.eventQueue) > 0 && cc.currentTime < totalHorizon {
+		select {
+		case <-ctx.Done():
+			return cc.stats(simulationTime)
+		default:
+		}
+		if !warmedUp && cc.eventQueue[0].timestamp >= cc.warmupTime {
+			cc.resetStatsAfterWarmup()
+			warmedUp = true
+		}
 		cc.ProcessNextEvent()
 	}
+	return cc.stats(simulationTime)
+}
+
+func (cc *CallCenter) stats(simulationTime int) Stats {
 	totalBusyTime := 0
 	for _, bt := range cc.busyTime {
 		totalBusyTime += bt
 	}
 	utilization := float64(totalBusyTime) / float64(cc.numAgents*simulationTime)
-	return cc.totalCalls, cc.abandonedCalls, utilization
+	return Stats{
+		TotalCalls:     cc.totalCalls,
+		AbandonedCalls: cc.abandonedCalls,
+		Utilization:    utilization,
+		Wait:           computePercentiles(cc.waitTimes),
+		Sojourn:        computePercentiles(cc.sojournTimes),
+		AbandonedWait:  computePercentiles(cc.abandonedWaitTimes),
+	}
 }
 
 func main() {
-	numAgents := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
-	simulationTime := 1440
-	maxWaitTimes := []int{5, 10, 15}
-	lambdas := []float64{0.5, 1, 1.5, 2}
-	averageCallTimes := []float64{3, 5, 7, 9}
+	opts := parseFlags()
 
-	rand.Seed(time.Now().UnixNano())
+	if opts.cpuProfile != "" {
+		f, err := os.Create(opts.cpuProfile)
+		if err != nil {
+			log.Fatalf("Error creating CPU profile: %v", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			log.Fatalf("Error starting CPU profile: %v", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		fmt.Println("Received interrupt, stopping in-flight scenarios and writing completed results...")
+		cancel()
+	}()
 
-	file, err := os.Create("simulation_results.csv")
+	file, err := os.Create(opts.output)
 	if err != nil {
 		log.Fatalf("Error creating CSV file: %v", err)
 	}
 	defer file.Close()
 
 	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	headers := []string{"NumAgents", "SimulationTime", "MaxWaitTime", "Lambda", "AverageCallTime", "TotalCalls", "AbandonedCalls", "Utilization"}
-	writer.Write(headers)
-
-	for _, agents := range numAgents {
-		for _, maxWait := range maxWaitTimes {
-			for _, lambda := range lambdas {
-				for _, avgCallTime := range averageCallTimes {
-					callCenter := NewCallCenter(agents, maxWait, lambda, avgCallTime)
-					totalCalls, abandonedCalls, utilization := callCenter.RunSimulation(simulationTime)
-					writer.Write([]string{
-						strconv.Itoa(agents),
-						strconv.Itoa(simulationTime),
-						strconv.Itoa(maxWait),
-						fmt.Sprintf("%.1f", lambda),
-						fmt.Sprintf("%.1f", avgCallTime),
-						strconv.Itoa(totalCalls),
-						strconv.Itoa(abandonedCalls),
-						fmt.Sprintf("%.2f", utilization*100),
-					})
-				}
-			}
+
+	scenarios := buildScenarios(opts)
+	completed := runSweep(ctx, scenarios, opts, writer)
+
+	if opts.memProfile != "" {
+		f, err := os.Create(opts.memProfile)
+		if err != nil {
+			log.Fatalf("Error creating memory profile: %v", err)
+		}
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			log.Fatalf("Error writing memory profile: %v", err)
 		}
+		f.Close()
 	}
 
-	fmt.Println("Simulation completed. Results written to simulation_results.csv")
+	fmt.Printf("Simulation completed (%d/%d scenarios). Results written to %s\n", completed, len(scenarios), opts.output)
 }
@@ -0,0 +1,97 @@
+package main
+
+import "math"
+
+// CIStat is a mean across replications plus the half-width of its 95% CI.
+type CIStat struct {
+	Mean      float64
+	HalfWidth float64
+}
+
+type ReplicatedStats struct {
+	Replications    int
+	Utilization     CIStat
+	AbandonmentRate CIStat
+	MeanWait        CIStat
+	Wait            Percentiles
+	Sojourn         Percentiles
+	AbandonedWait   Percentiles
+}
+
+func confidenceInterval(samples []float64) CIStat {
+	n := len(samples)
+	if n == 0 {
+		return CIStat{}
+	}
+	sum := 0.0
+	for _, s := range samples {
+		sum += s
+	}
+	mean := sum / float64(n)
+	if n == 1 {
+		return CIStat{Mean: mean}
+	}
+	variance := 0.0
+	for _, s := range samples {
+		diff := s - mean
+		variance += diff * diff
+	}
+	variance /= float64(n - 1)
+	stddev := math.Sqrt(variance)
+	return CIStat{Mean: mean, HalfWidth: 1.96 * stddev / math.Sqrt(float64(n))}
+}
+
+func averagePercentiles(all []Percentiles) Percentiles {
+	n := float64(len(all))
+	if n == 0 {
+		return Percentiles{}
+	}
+	var avg Percentiles
+	for _, p := range all {
+		avg.Min += p.Min
+		avg.Mean += p.Mean
+		avg.P50 += p.P50
+		avg.P90 += p.P90
+		avg.P95 += p.P95
+		avg.P99 += p.P99
+		avg.Max += p.Max
+	}
+	avg.Min /= n
+	avg.Mean /= n
+	avg.P50 /= n
+	avg.P90 /= n
+	avg.P95 /= n
+	avg.P99 /= n
+	avg.Max /= n
+	return avg
+}
+
+func summarizeReplications(reps []Stats) ReplicatedStats {
+	utilSamples := make([]float64, len(reps))
+	abandonRateSamples := make([]float64, len(reps))
+	meanWaitSamples := make([]float64, len(reps))
+	waitPercentiles := make([]Percentiles, len(reps))
+	sojournPercentiles := make([]Percentiles, len(reps))
+	abandonedWaitPercentiles := make([]Percentiles, len(reps))
+
+	for i, s := range reps {
+		utilSamples[i] = s.Utilization
+		if s.TotalCalls > 0 {
+			abandonRateSamples[i] = float64(s.AbandonedCalls) / float64(s.TotalCalls)
+		}
+		meanWaitSamples[i] = s.Wait.Mean
+		waitPercentiles[i] = s.Wait
+		sojournPercentiles[i] = s.Sojourn
+		abandonedWaitPercentiles[i] = s.AbandonedWait
+	}
+
+	return ReplicatedStats{
+		Replications:    len(reps),
+		Utilization:     confidenceInterval(utilSamples),
+		AbandonmentRate: confidenceInterval(abandonRateSamples),
+		MeanWait:        confidenceInterval(meanWaitSamples),
+		Wait:            averagePercentiles(waitPercentiles),
+		Sojourn:         averagePercentiles(sojournPercentiles),
+		AbandonedWait:   averagePercentiles(abandonedWaitPercentiles),
+	}
+}
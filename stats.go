@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Percentiles struct {
+	Min  float64
+	Mean float64
+	P50  float64
+	P90  float64
+	P95  float64
+	P99  float64
+	Max  float64
+}
+
+type Stats struct {
+	TotalCalls     int
+	AbandonedCalls int
+	Utilization    float64
+	Wait           Percentiles
+	Sojourn        Percentiles
+	AbandonedWait  Percentiles
+}
+
+func computePercentiles(samples []int) Percentiles {
+	if len(samples) == 0 {
+		return Percentiles{}
+	}
+	sorted := make([]int, len(samples))
+	copy(sorted, samples)
+	sort.Ints(sorted)
+
+	sum := 0
+	for _, s := range sorted {
+		sum += s
+	}
+
+	return Percentiles{
+		Min:  float64(sorted[0]),
+		Mean: float64(sum) / float64(len(sorted)),
+		P50:  float64(percentileAt(sorted, 0.50)),
+		P90:  float64(percentileAt(sorted, 0.90)),
+		P95:  float64(percentileAt(sorted, 0.95)),
+		P99:  float64(percentileAt(sorted, 0.99)),
+		Max:  float64(sorted[len(sorted)-1]),
+	}
+}
+
+// percentileAt returns the value at fraction p of a sorted slice.
+func percentileAt(sorted []int, p float64) int {
+	idx := int(float64(len(sorted)) * p)
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// percentilesToRow formats a Percentiles value as CSV fields, in the
+// same min/mean/p50/p90/p95/p99/max order as the struct.
+func percentilesToRow(p Percentiles) []string {
+	return []string{
+		fmt.Sprintf("%.2f", p.Min),
+		fmt.Sprintf("%.2f", p.Mean),
+		fmt.Sprintf("%.2f", p.P50),
+		fmt.Sprintf("%.2f", p.P90),
+		fmt.Sprintf("%.2f", p.P95),
+		fmt.Sprintf("%.2f", p.P99),
+		fmt.Sprintf("%.2f", p.Max),
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Scenario struct {
+	Agents      int
+	MaxWaitTime int
+	ArrivalSpec DistSpec
+	ServiceSpec DistSpec
+}
+
+type Result struct {
+	Scenario
+	ReplicatedStats
+}
+
+type sweepOptions struct {
+	numAgents      []int
+	maxWaitTimes   []int
+	arrivalSpecs   []DistSpec
+	serviceSpecs   []DistSpec
+	simulationTime int
+	warmupTime     int
+	replications   int
+	workers        int
+	output         string
+	cpuProfile     string
+	memProfile     string
+	seed           int64
+}
+
+func parseFlags() sweepOptions {
+	numAgentsFlag := flag.String("agents", "1,2,3,4,5,6,7,8,9,10", "comma-separated list of agent counts to sweep")
+	maxWaitTimesFlag := flag.String("max-wait", "5,10,15", "comma-separated list of max wait times (minutes) to sweep")
+	arrivalFlag := flag.String("arrival", "exp:1.0", "\";\"-separated list of inter-arrival distributions to sweep: exp:lambda | det:value | erlang:k,mean | lognormal:mu,sigma | hyperexp:p,rate1,rate2")
+	serviceFlag := flag.String("service", "exp:0.2", "\";\"-separated list of service time distributions to sweep, same specifier syntax as -arrival")
+	simulationTimeFlag := flag.Int("simulation-time", 1440, "simulated duration in minutes for each scenario, measured after warmup")
+	warmupTimeFlag := flag.Int("warmup", 0, "simulated warmup duration in minutes; samples from this window are discarded")
+	replicationsFlag := flag.Int("replications", 1, "number of independent replications to run per scenario")
+	workersFlag := flag.Int("workers", runtime.NumCPU(), "number of worker goroutines running scenarios concurrently")
+	outputFlag := flag.String("output", "simulation_results.csv", "path to write the results CSV to")
+	cpuProfileFlag := flag.String("cpuprofile", "", "write a CPU profile to this path")
+	memProfileFlag := flag.String("memprofile", "", "write a heap profile to this path")
+	seedFlag := flag.Int64("seed", time.Now().UnixNano(), "base RNG seed; each scenario's generator is seeded from it plus the scenario's index, so fixing it reproduces a run's results")
+	flag.Parse()
+
+	numAgents, err := parseIntList(*numAgentsFlag)
+	if err != nil {
+		log.Fatalf("invalid -agents: %v", err)
+	}
+	maxWaitTimes, err := parseIntList(*maxWaitTimesFlag)
+	if err != nil {
+		log.Fatalf("invalid -max-wait: %v", err)
+	}
+	arrivalSpecs, err := parseDistSpecList(*arrivalFlag)
+	if err != nil {
+		log.Fatalf("invalid -arrival: %v", err)
+	}
+	serviceSpecs, err := parseDistSpecList(*serviceFlag)
+	if err != nil {
+		log.Fatalf("invalid -service: %v", err)
+	}
+
+	return sweepOptions{
+		numAgents:      numAgents,
+		maxWaitTimes:   maxWaitTimes,
+		arrivalSpecs:   arrivalSpecs,
+		serviceSpecs:   serviceSpecs,
+		simulationTime: *simulationTimeFlag,
+		warmupTime:     *warmupTimeFlag,
+		replications:   *replicationsFlag,
+		workers:        *workersFlag,
+		output:         *outputFlag,
+		cpuProfile:     *cpuProfileFlag,
+		memProfile:     *memProfileFlag,
+		seed:           *seedFlag,
+	}
+}
+
+func parseIntList(s string) ([]int, error) {
+	var values []int
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", part, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func buildScenarios(opts sweepOptions) []Scenario {
+	var scenarios []Scenario
+	for _, agents := range opts.numAgents {
+		for _, maxWait := range opts.maxWaitTimes {
+			for _, arrivalSpec := range opts.arrivalSpecs {
+				for _, serviceSpec := range opts.serviceSpecs {
+					scenarios = append(scenarios, Scenario{
+						Agents:      agents,
+						MaxWaitTime: maxWait,
+						ArrivalSpec: arrivalSpec,
+						ServiceSpec: serviceSpec,
+					})
+				}
+			}
+		}
+	}
+	return scenarios
+}
+
+var csvHeaders = []string{
+	"NumAgents", "SimulationTime", "WarmupTime", "MaxWaitTime",
+	"ArrivalDist", "ArrivalCV", "ServiceDist", "ServiceCV", "Replications",
+	"UtilizationMean", "UtilizationCI95", "AbandonmentRateMean", "AbandonmentRateCI95", "MeanWaitMean", "MeanWaitCI95",
+	"WaitMin", "WaitMean", "WaitP50", "WaitP90", "WaitP95", "WaitP99", "WaitMax",
+	"SojournMin", "SojournMean", "SojournP50", "SojournP90", "SojournP95", "SojournP99", "SojournMax",
+	"AbandonedWaitMin", "AbandonedWaitMean", "AbandonedWaitP50", "AbandonedWaitP90", "AbandonedWaitP95", "AbandonedWaitP99", "AbandonedWaitMax",
+}
+
+func resultToRow(res Result, opts sweepOptions) []string {
+	row := []string{
+		strconv.Itoa(res.Agents),
+		strconv.Itoa(opts.simulationTime),
+		strconv.Itoa(opts.warmupTime),
+		strconv.Itoa(res.MaxWaitTime),
+		res.ArrivalSpec.Name,
+		fmt.Sprintf("%.4f", distCV(res.ArrivalSpec)),
+		res.ServiceSpec.Name,
+		fmt.Sprintf("%.4f", distCV(res.ServiceSpec)),
+		strconv.Itoa(res.Replications),
+		fmt.Sprintf("%.4f", res.Utilization.Mean),
+		fmt.Sprintf("%.4f", res.Utilization.HalfWidth),
+		fmt.Sprintf("%.4f", res.AbandonmentRate.Mean),
+		fmt.Sprintf("%.4f", res.AbandonmentRate.HalfWidth),
+		fmt.Sprintf("%.2f", res.MeanWait.Mean),
+		fmt.Sprintf("%.2f", res.MeanWait.HalfWidth),
+	}
+	row = append(row, percentilesToRow(res.Wait)...)
+	row = append(row, percentilesToRow(res.Sojourn)...)
+	row = append(row, percentilesToRow(res.AbandonedWait)...)
+	return row
+}
+
+// runSweep fans scenarios out across opts.workers goroutines and has a
+// single collector goroutine own the csv.Writer so rows never interleave.
+func runSweep(ctx context.Context, scenarios []Scenario, opts sweepOptions, writer *csv.Writer) int {
+	scenarioCh := make(chan int)
+	resultCh := make(chan Result)
+	var completed int64
+
+	var workers sync.WaitGroup
+	for w := 0; w < opts.workers; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range scenarioCh {
+				sc := scenarios[i]
+				// Seed from the scenario's index, not the worker's, so results
+				// do not depend on which worker picks the scenario up.
+				rng := rand.New(rand.NewSource(opts.seed + int64(i)))
+				reps := make([]Stats, opts.replications)
+				for r := 0; r < opts.replications; r++ {
+					repRng := rand.New(rand.NewSource(rng.Int63()))
+					arrivalDist, err := newDistribution(sc.ArrivalSpec, repRng)
+					if err != nil {
+						log.Fatalf("building arrival distribution: %v", err)
+					}
+					serviceDist, err := newDistribution(sc.ServiceSpec, repRng)
+					if err != nil {
+						log.Fatalf("building service distribution: %v", err)
+					}
+					cc := NewCallCenter(sc.Agents, sc.MaxWaitTime, arrivalDist, serviceDist, opts.warmupTime)
+					reps[r] = cc.RunSimulation(ctx, opts.simulationTime)
+				}
+				if ctx.Err() != nil {
+					// Replications cut short by cancellation are not written.
+					continue
+				}
+				resultCh <- Result{Scenario: sc, ReplicatedStats: summarizeReplications(reps)}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(scenarioCh)
+		for i := range scenarios {
+			select {
+			case scenarioCh <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	progressDone := make(chan struct{})
+	go reportProgress(ctx, &completed, len(scenarios), progressDone)
+
+	collectorDone := make(chan struct{})
+	go func() {
+		defer close(collectorDone)
+		writer.Write(csvHeaders)
+		for res := range resultCh {
+			writer.Write(resultToRow(res, opts))
+			atomic.AddInt64(&completed, 1)
+		}
+		writer.Flush()
+	}()
+
+	workers.Wait()
+	close(resultCh)
+	<-collectorDone
+	close(progressDone)
+
+	return int(atomic.LoadInt64(&completed))
+}
+
+func reportProgress(ctx context.Context, completed *int64, total int, done chan struct{}) {
+	if total == 0 {
+		return
+	}
+	start := time.Now()
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n := atomic.LoadInt64(completed)
+			if n == 0 {
+				continue
+			}
+			elapsed := time.Since(start)
+			eta := elapsed * time.Duration(int64(total)-n) / time.Duration(n)
+			log.Printf("progress: %d/%d scenarios completed, ETA %s", n, total, eta.Round(time.Second))
+		}
+	}
+}